@@ -21,8 +21,9 @@ import (
 	"fmt"
 	"io"
 	"math/rand"
-	"reflect"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gorilla/rpc/v2/json2"
 	"github.com/gorilla/websocket"
@@ -30,26 +31,86 @@ import (
 	"go.uber.org/zap"
 )
 
-type result interface{}
+const (
+	defaultMaxReconnectAttempts = 10
+	defaultReconnectBaseDelay   = 500 * time.Millisecond
+	defaultReconnectMaxDelay    = 30 * time.Second
+	defaultPingInterval         = 15 * time.Second
+	defaultPongTimeout          = 45 * time.Second
+)
 
 type WSClient struct {
 	rpcURL                  string
 	conn                    *websocket.Conn
 	lock                    sync.RWMutex
-	subscriptionByRequestID map[uint64]*Subscription
-	subscriptionByWSSubID   map[uint64]*Subscription
+	subscriptionByRequestID map[uint64]subscription
+	subscriptionByWSSubID   map[uint64]subscription
 	reconnectOnErr          bool
+
+	maxReconnectAttempts int
+	reconnectBaseDelay   time.Duration
+	reconnectMaxDelay    time.Duration
+	pingInterval         time.Duration
+	pongTimeout          time.Duration
+
+	notify chan error
+	closed bool
+}
+
+// DialOption customizes the reconnection and heartbeat behavior of a
+// WSClient; pass any number to Dial.
+type DialOption func(*WSClient)
+
+// WithReconnectOnErr enables or disables automatic reconnection on read
+// errors. It is enabled by default.
+func WithReconnectOnErr(enabled bool) DialOption {
+	return func(c *WSClient) { c.reconnectOnErr = enabled }
+}
+
+// WithMaxReconnectAttempts caps how many times reconnect will redial before
+// giving up. A value <= 0 means retry forever.
+func WithMaxReconnectAttempts(n int) DialOption {
+	return func(c *WSClient) { c.maxReconnectAttempts = n }
 }
 
-func Dial(ctx context.Context, rpcURL string) (c *WSClient, err error) {
+// WithReconnectBackoff sets the base and max delays used by reconnect's
+// exponential backoff with jitter.
+func WithReconnectBackoff(base, max time.Duration) DialOption {
+	return func(c *WSClient) {
+		c.reconnectBaseDelay = base
+		c.reconnectMaxDelay = max
+	}
+}
+
+// WithHeartbeat sets the timeouts used to detect a silently dead connection.
+// The client does not send pings itself: pongTimeout is how long reads may
+// go without a ping from the server before they fail (only received pings
+// renew it), and pingInterval is the write deadline for each pong reply.
+func WithHeartbeat(pingInterval, pongTimeout time.Duration) DialOption {
+	return func(c *WSClient) {
+		c.pingInterval = pingInterval
+		c.pongTimeout = pongTimeout
+	}
+}
+
+func Dial(ctx context.Context, rpcURL string, opts ...DialOption) (c *WSClient, err error) {
 	c = &WSClient{
 		rpcURL:                  rpcURL,
-		subscriptionByRequestID: map[uint64]*Subscription{},
-		subscriptionByWSSubID:   map[uint64]*Subscription{},
+		subscriptionByRequestID: map[uint64]subscription{},
+		subscriptionByWSSubID:   map[uint64]subscription{},
+		reconnectOnErr:          true,
+		maxReconnectAttempts:    defaultMaxReconnectAttempts,
+		reconnectBaseDelay:      defaultReconnectBaseDelay,
+		reconnectMaxDelay:       defaultReconnectMaxDelay,
+		pingInterval:            defaultPingInterval,
+		pongTimeout:             defaultPongTimeout,
 	}
 
-	c.conn, _, err = websocket.DefaultDialer.DialContext(ctx, rpcURL, nil)
-	if err != nil {
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if err := c.dial(ctx); err != nil {
 		return nil, fmt.Errorf("new ws client: dial: %w", err)
 	}
 
@@ -57,14 +118,75 @@ func Dial(ctx context.Context, rpcURL string) (c *WSClient, err error) {
 	return c, nil
 }
 
+func (c *WSClient) dial(ctx context.Context) (err error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, c.rpcURL, nil)
+	if err != nil {
+		return err
+	}
+
+	// The Solana validator pings idle connections; answer with the default
+	// pong reply but also push the read deadline forward so a validator
+	// that goes silent (network partition, crash) surfaces as a
+	// ReadMessage error instead of hanging forever.
+	conn.SetPingHandler(func(appData string) error {
+		conn.SetReadDeadline(time.Now().Add(c.pongTimeout))
+		return conn.WriteControl(websocket.PongMessage, []byte(appData), time.Now().Add(c.pingInterval))
+	})
+	conn.SetReadDeadline(time.Now().Add(c.pongTimeout))
+
+	c.lock.Lock()
+	c.conn = conn
+	c.lock.Unlock()
+
+	return nil
+}
+
+// getConn returns the current connection under lock. Every reader outside
+// of dial() must go through this instead of touching c.conn directly, since
+// reconnect() replaces it concurrently with receiveMessages/subscribe/etc.
+func (c *WSClient) getConn() *websocket.Conn {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.conn
+}
+
+// Notify registers a channel on which the client reports reconnection
+// related events (the error that triggered a reconnect, or nil once
+// resubscription succeeds). It is safe to call only once per client.
+func (c *WSClient) Notify(ch chan error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.notify = ch
+}
+
+func (c *WSClient) notifyAsync(err error) {
+	c.lock.RLock()
+	ch := c.notify
+	c.lock.RUnlock()
+	if ch == nil {
+		return
+	}
+	select {
+	case ch <- err:
+	default:
+	}
+}
+
 func (c *WSClient) Close() {
-	c.conn.Close()
+	c.lock.Lock()
+	c.closed = true
+	conn := c.conn
+	c.lock.Unlock()
+	conn.Close()
 }
 
 func (c *WSClient) receiveMessages() {
 	for {
-		_, message, err := c.conn.ReadMessage()
+		_, message, err := c.getConn().ReadMessage()
 		if err != nil {
+			if c.reconnectOnErr && c.reconnect(err) {
+				continue
+			}
 			c.closeAllSubscription(err)
 			return
 		}
@@ -72,6 +194,98 @@ func (c *WSClient) receiveMessages() {
 	}
 }
 
+// reconnect redials rpcURL with exponential backoff and jitter, then
+// re-issues every stored subscription request so callers keep receiving
+// on their existing Recv() channels. It returns false (giving up on the
+// connection) if the client was closed or maxReconnectAttempts is exhausted.
+func (c *WSClient) reconnect(cause error) bool {
+	if c.isClosed() {
+		return false
+	}
+
+	zlog.Warn("ws connection lost, attempting to reconnect", zap.Error(cause))
+	c.notifyAsync(cause)
+
+	delay := c.reconnectBaseDelay
+	for attempt := 1; c.maxReconnectAttempts <= 0 || attempt <= c.maxReconnectAttempts; attempt++ {
+		if c.isClosed() {
+			zlog.Info("ws client closed while reconnecting, aborting")
+			return false
+		}
+
+		jitter := time.Duration(rand.Int63n(int64(delay) + 1))
+		time.Sleep(delay/2 + jitter/2)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err := c.dial(ctx)
+		cancel()
+		if err != nil {
+			zlog.Warn("reconnect attempt failed", zap.Int("attempt", attempt), zap.Error(err))
+			delay *= 2
+			if delay > c.reconnectMaxDelay {
+				delay = c.reconnectMaxDelay
+			}
+			continue
+		}
+
+		if c.isClosed() {
+			// Close() ran while we were dialing; nothing else will ever
+			// close this freshly dialed connection, so do it ourselves.
+			zlog.Info("ws client closed while reconnecting, aborting")
+			c.getConn().Close()
+			return false
+		}
+
+		if err := c.resubscribeAll(); err != nil {
+			zlog.Warn("reconnected but failed to resubscribe", zap.Error(err))
+			c.getConn().Close()
+			delay *= 2
+			if delay > c.reconnectMaxDelay {
+				delay = c.reconnectMaxDelay
+			}
+			continue
+		}
+
+		zlog.Info("ws client reconnected", zap.Int("attempt", attempt))
+		c.notifyAsync(nil)
+		return true
+	}
+
+	zlog.Warn("giving up on reconnecting ws client", zap.Int("attempts", c.maxReconnectAttempts))
+	return false
+}
+
+func (c *WSClient) isClosed() bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.closed
+}
+
+// resubscribeAll re-issues every stored clientRequest over the freshly
+// dialed connection. Each Subscription keeps its original stream/err
+// channels; only its subID (and the subscriptionByWSSubID mapping) is
+// replaced once the server assigns a new one.
+func (c *WSClient) resubscribeAll() error {
+	c.lock.Lock()
+	subs := make([]subscription, 0, len(c.subscriptionByRequestID))
+	for _, sub := range c.subscriptionByRequestID {
+		subs = append(subs, sub)
+	}
+	c.subscriptionByWSSubID = map[uint64]subscription{}
+	c.lock.Unlock()
+
+	for _, sub := range subs {
+		data, err := sub.request().encode()
+		if err != nil {
+			return fmt.Errorf("resubscribe: unable to encode subscription request: %w", err)
+		}
+		if err := c.getConn().WriteMessage(websocket.TextMessage, data); err != nil {
+			return fmt.Errorf("resubscribe: unable to write request: %w", err)
+		}
+	}
+	return nil
+}
+
 func (c *WSClient) handleMessage(message []byte) {
 	// when receiving message with id. the result will be a subscription number.
 	// that number will be associated to all future message destine to this request
@@ -82,26 +296,38 @@ func (c *WSClient) handleMessage(message []byte) {
 		return
 	}
 
-	c.handleSubscriptionMessage(uint64(gjson.GetBytes(message, "params.subscription").Int()), message)
-
+	method := gjson.GetBytes(message, "method").String()
+	subID := uint64(gjson.GetBytes(message, "params.subscription").Int())
+	c.handleSubscriptionMessage(method, subID, message)
 }
 
 func (c *WSClient) handleNewSubscriptionMessage(requestID, subID uint64) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
+	callBack, found := c.subscriptionByRequestID[requestID]
+	if !found {
+		// Unsubscribe acks carry a fresh request ID that was never stored
+		// (rpcUnsubscribe doesn't register one), and any other stray/late
+		// response would land here too. Neither is a callback we can key.
+		zlog.Debug("received new subscription message for unknown request id",
+			zap.Uint64("message_id", requestID),
+			zap.Uint64("subscription_id", subID),
+		)
+		return
+	}
+
 	zlog.Info("received new subscription message",
 		zap.Uint64("message_id", requestID),
 		zap.Uint64("subscription_id", subID),
 	)
-	callBack := c.subscriptionByRequestID[requestID]
-	callBack.subID = subID
+	callBack.setSubID(subID)
 	c.subscriptionByWSSubID[subID] = callBack
-	return
 }
 
-func (c *WSClient) handleSubscriptionMessage(subID uint64, message []byte) {
+func (c *WSClient) handleSubscriptionMessage(method string, subID uint64, message []byte) {
 	zlog.Info("received subscription message",
+		zap.String("method", method),
 		zap.Uint64("subscription_id", subID),
 	)
 
@@ -113,24 +339,47 @@ func (c *WSClient) handleSubscriptionMessage(subID uint64, message []byte) {
 		return
 	}
 
-	//getting and instantiate the return type for the call back.
-	resultType := reflect.New(sub.reflectType)
-	result := resultType.Interface()
-	err := decodeClientResponse(bytes.NewReader(message), &result)
-	if err != nil {
-		c.closeSubscription(sub.req.ID, fmt.Errorf("unable to decode client response: %w", err))
+	if !notificationMatchesNamespace(method, sub.namespace()) {
+		zlog.Warn("dropping notification for a different namespace than the subscription's",
+			zap.String("method", method),
+			zap.String("namespace", sub.namespace()),
+			zap.Uint64("subscription_id", subID),
+		)
 		return
 	}
 
-	// this cannot be blocking or else
-	// we  will no read any other message
-	if len(sub.stream) >= cap(sub.stream) {
-		c.closeSubscription(sub.req.ID, fmt.Errorf("reached channel max capacity %d", len(sub.stream)))
+	// dispatch decodes message into the subscription's typed channel; it
+	// cannot be blocking or else we will not read any other message.
+	if err := sub.dispatch(message); err != nil {
+		c.closeSubscription(sub.request().ID, err)
 		return
 	}
 
-	sub.stream <- result
-	return
+	if sub.isOneShot() {
+		c.closeSubscription(sub.request().ID, io.EOF)
+	}
+}
+
+// notificationSuffixes lists the method-name endings different JSON-RPC
+// pubsub servers use for subscription notifications: Solana emits
+// "<namespace>Notification" (e.g. "accountNotification"), while some other
+// JSON-RPC servers emit "<namespace>Subscription" instead.
+var notificationSuffixes = []string{"Notification", "Subscription"}
+
+// notificationMatchesNamespace reports whether method looks like a
+// notification belonging to namespace. Servers that omit method on
+// notification frames pass the check, since subID routing already
+// identified the subscription in that case.
+func notificationMatchesNamespace(method, namespace string) bool {
+	if method == "" {
+		return true
+	}
+	for _, suffix := range notificationSuffixes {
+		if method == namespace+suffix {
+			return true
+		}
+	}
+	return false
 }
 
 func (c *WSClient) closeAllSubscription(err error) {
@@ -138,33 +387,35 @@ func (c *WSClient) closeAllSubscription(err error) {
 	defer c.lock.Unlock()
 
 	for _, sub := range c.subscriptionByRequestID {
-		sub.err <- err
+		sub.closeWithErr(err)
 	}
 
-	c.subscriptionByRequestID = map[uint64]*Subscription{}
-	c.subscriptionByWSSubID = map[uint64]*Subscription{}
+	c.subscriptionByRequestID = map[uint64]subscription{}
+	c.subscriptionByWSSubID = map[uint64]subscription{}
 }
 
 func (c *WSClient) closeSubscription(reqID uint64, err error) {
 	c.lock.Lock()
-	defer c.lock.Unlock()
-
 	sub, found := c.subscriptionByRequestID[reqID]
 	if !found {
+		c.lock.Unlock()
 		return
 	}
 
-	sub.err <- err
+	delete(c.subscriptionByRequestID, sub.request().ID)
+	delete(c.subscriptionByWSSubID, sub.subID())
+	c.lock.Unlock()
 
-	err = c.rpcUnsubscribe(sub.subID, sub.unsubscriptionMethod)
-	if err != nil {
+	sub.closeWithErr(err)
+
+	// rpcUnsubscribe takes c.lock itself (via getConn), so it must run
+	// after we've released it above or it deadlocks against this goroutine.
+	unsubErr := c.rpcUnsubscribe(sub.subID(), sub.unsubscribeMethod())
+	if unsubErr != nil {
 		zlog.Warn("unable to send rpc unsubscribe call",
-			zap.Error(err),
+			zap.Error(unsubErr),
 		)
 	}
-
-	delete(c.subscriptionByRequestID, sub.req.ID)
-	delete(c.subscriptionByWSSubID, sub.subID)
 }
 
 func (c *WSClient) rpcUnsubscribe(subID uint64, method string) error {
@@ -174,70 +425,409 @@ func (c *WSClient) rpcUnsubscribe(subID uint64, method string) error {
 		return fmt.Errorf("unable to encode unsubscription message for subID %d and method %s", subID, method)
 	}
 
-	err = c.conn.WriteMessage(websocket.TextMessage, data)
+	err = c.getConn().WriteMessage(websocket.TextMessage, data)
 	if err != nil {
 		return fmt.Errorf("unable to send unsubscription message for subID %d and method %s", subID, method)
 	}
 	return nil
 }
 
-type Subscription struct {
+// subscription is the non-generic view of a Subscription[T] that the
+// WSClient dispatcher needs: it can decode a raw message into its own
+// typed channel without the dispatcher knowing the concrete T.
+type subscription interface {
+	request() *clientRequest
+	subID() uint64
+	setSubID(id uint64)
+	namespace() string
+	unsubscribeMethod() string
+	isOneShot() bool
+	dispatch(message []byte) error
+	closeWithErr(err error)
+}
+
+// BackpressurePolicy controls what a Subscription does when its consumer
+// isn't keeping up with the buffered channel.
+type BackpressurePolicy int
+
+const (
+	// BackpressureCloseOnOverflow closes the subscription (and sends an
+	// unsubscribe) as soon as a message arrives while its buffer is already
+	// full; that message is not delivered. This is the historical
+	// behavior and the default: it's the harshest policy but guarantees a
+	// slow consumer never silently misses messages without knowing it.
+	BackpressureCloseOnOverflow BackpressurePolicy = iota
+	// BackpressureBlockWithTimeout blocks delivery for up to the
+	// subscription's blockTimeout, dropping the message if the consumer
+	// hasn't drained the buffer by then.
+	BackpressureBlockWithTimeout
+	// BackpressureDropOldest evicts the oldest buffered message to make
+	// room for the new one, favoring freshness over completeness.
+	BackpressureDropOldest
+	// BackpressureDropNewest discards the incoming message when the
+	// buffer is full, favoring in-order delivery of what's already buffered.
+	BackpressureDropNewest
+)
+
+const (
+	defaultSubscriptionBufferSize = 200
+	defaultBackpressureTimeout    = 5 * time.Second
+)
+
+// MetricsRecorder is an injectable sink for per-subscription metrics.
+// Implementations are expected to be safe for concurrent use and to export
+// these as Prometheus-style counters/gauges keyed by namespace.
+type MetricsRecorder interface {
+	IncMessagesReceived(namespace string)
+	IncMessagesDropped(namespace string)
+	IncDecodeErrors(namespace string)
+	ObserveChannelHighWatermark(namespace string, watermark int)
+	ObserveSubscriptionUptime(namespace string, seconds float64)
+}
+
+type noopMetricsRecorder struct{}
+
+func (noopMetricsRecorder) IncMessagesReceived(string)                {}
+func (noopMetricsRecorder) IncMessagesDropped(string)                 {}
+func (noopMetricsRecorder) IncDecodeErrors(string)                    {}
+func (noopMetricsRecorder) ObserveChannelHighWatermark(string, int)   {}
+func (noopMetricsRecorder) ObserveSubscriptionUptime(string, float64) {}
+
+// Subscription is a typed handle on a single Solana pubsub subscription.
+// Recv returns results decoded straight into *T, so call sites no longer
+// need a type assertion against an interface{} channel.
+type Subscription[T any] struct {
 	req                  *clientRequest
-	subID                uint64
-	stream               chan result
+	sID                  uint64
+	stream               chan *T
 	err                  chan error
-	reflectType          reflect.Type
 	closeFunc            func(err error)
+	ns                   string
 	unsubscriptionMethod string
+	// oneShotSub subscriptions (e.g. signatureSubscribe) close themselves
+	// right after their first notification is delivered on stream.
+	oneShotSub bool
+
+	policy       BackpressurePolicy
+	blockTimeout time.Duration
+	metrics      MetricsRecorder
+	createdAt    time.Time
+
+	dropped       uint64
+	highWatermark int32
 }
 
-func newSubscription(req *clientRequest, reflectType reflect.Type, closeFunc func(err error)) *Subscription {
-	return &Subscription{
-		req:         req,
-		reflectType: reflectType,
-		stream:      make(chan result, 200),
-		err:         make(chan error, 1),
-		closeFunc:   closeFunc,
+func newSubscription[T any](req *clientRequest, namespace, unsubscriptionMethod string, oneShot bool, cfg *subscribeConfig, closeFunc func(err error)) *Subscription[T] {
+	return &Subscription[T]{
+		req:                  req,
+		stream:               make(chan *T, cfg.bufferSize),
+		err:                  make(chan error, 1),
+		closeFunc:            closeFunc,
+		ns:                   namespace,
+		unsubscriptionMethod: unsubscriptionMethod,
+		oneShotSub:           oneShot,
+		policy:               cfg.policy,
+		blockTimeout:         cfg.blockTimeout,
+		metrics:              cfg.metrics,
+		createdAt:            time.Now(),
 	}
 }
 
-func (s *Subscription) Recv() (interface{}, error) {
+// Recv blocks until the next decoded value or a terminal error (e.g. the
+// read error that tore down the connection, or io.EOF for a one-shot
+// subscription). It always prefers a pending value over a terminal error:
+// dispatch enqueues onto stream strictly before a terminal error reaches
+// err, so a value already sitting in stream is never superseded by a
+// same-instant error under select's pseudo-random case choice.
+func (s *Subscription[T]) Recv() (*T, error) {
+	select {
+	case d := <-s.stream:
+		return d, nil
+	default:
+	}
+
 	select {
 	case d := <-s.stream:
 		return d, nil
 	case err := <-s.err:
+		select {
+		case d := <-s.stream:
+			s.err <- err // not consumed yet; next Recv call still needs it
+			return d, nil
+		default:
+		}
 		return nil, err
 	}
 }
 
-func (s *Subscription) Unsubscribe() {
-	s.unsubscribe(nil)
+func (s *Subscription[T]) Unsubscribe() {
+	s.closeFunc(nil)
 }
 
-func (s *Subscription) unsubscribe(err error) {
-	s.closeFunc(err)
+// Namespace returns the subscription's JSON-RPC namespace (e.g. "account",
+// "program"), useful for logging and metrics.
+func (s *Subscription[T]) Namespace() string { return s.ns }
 
+// Method returns the JSON-RPC method used to create this subscription
+// (e.g. "accountSubscribe").
+func (s *Subscription[T]) Method() string { return s.req.Method }
+
+// DroppedCount returns how many messages this subscription's backpressure
+// policy has discarded so far, letting consumers detect gaps deterministically.
+func (s *Subscription[T]) DroppedCount() uint64 {
+	return atomic.LoadUint64(&s.dropped)
 }
 
-func (c *WSClient) ProgramSubscribe(programID string, commitment CommitmentType) (*Subscription, error) {
-	return c.subscribe([]interface{}{programID}, "programSubscribe", "programUnsubscribe", commitment, ProgramWSResult{})
+func (s *Subscription[T]) namespace() string         { return s.ns }
+func (s *Subscription[T]) request() *clientRequest   { return s.req }
+func (s *Subscription[T]) subID() uint64             { return s.sID }
+func (s *Subscription[T]) setSubID(id uint64)        { s.sID = id }
+func (s *Subscription[T]) unsubscribeMethod() string { return s.unsubscriptionMethod }
+func (s *Subscription[T]) isOneShot() bool           { return s.oneShotSub }
+
+func (s *Subscription[T]) closeWithErr(err error) {
+	s.metrics.ObserveSubscriptionUptime(s.ns, time.Since(s.createdAt).Seconds())
+	s.err <- err
 }
 
-func (c *WSClient) subscribe(params []interface{}, subscriptionMethod, unsubscriptionMethod string, commitment CommitmentType, resultType interface{}) (*Subscription, error) {
-	conf := map[string]interface{}{
-		"encoding": "jsonParsed",
+func (s *Subscription[T]) dispatch(message []byte) error {
+	var reply T
+	if err := decodeClientResponse(bytes.NewReader(message), &reply); err != nil {
+		s.metrics.IncDecodeErrors(s.ns)
+		return fmt.Errorf("unable to decode client response: %w", err)
+	}
+	s.metrics.IncMessagesReceived(s.ns)
+
+	if err := s.deliver(&reply); err != nil {
+		return err
+	}
+
+	s.observeHighWatermark()
+	return nil
+}
+
+// deliver applies the subscription's BackpressurePolicy to send v on
+// stream. It cannot block indefinitely or we'd stop reading off the
+// websocket entirely.
+func (s *Subscription[T]) deliver(v *T) error {
+	switch s.policy {
+	case BackpressureBlockWithTimeout:
+		select {
+		case s.stream <- v:
+		case <-time.After(s.blockTimeout):
+			s.recordDrop()
+		}
+
+	case BackpressureDropOldest:
+		select {
+		case s.stream <- v:
+		default:
+			select {
+			case <-s.stream:
+				s.recordDrop()
+			default:
+			}
+			select {
+			case s.stream <- v:
+			default:
+				s.recordDrop()
+			}
+		}
+
+	case BackpressureDropNewest:
+		select {
+		case s.stream <- v:
+		default:
+			s.recordDrop()
+		}
+
+	default: // BackpressureCloseOnOverflow
+		if len(s.stream) >= cap(s.stream) {
+			return fmt.Errorf("reached channel max capacity %d", cap(s.stream))
+		}
+		s.stream <- v
+	}
+
+	return nil
+}
+
+func (s *Subscription[T]) recordDrop() {
+	atomic.AddUint64(&s.dropped, 1)
+	s.metrics.IncMessagesDropped(s.ns)
+}
+
+func (s *Subscription[T]) observeHighWatermark() {
+	depth := int32(len(s.stream))
+	for {
+		cur := atomic.LoadInt32(&s.highWatermark)
+		if depth <= cur {
+			return
+		}
+		if atomic.CompareAndSwapInt32(&s.highWatermark, cur, depth) {
+			s.metrics.ObserveChannelHighWatermark(s.ns, int(depth))
+			return
+		}
+	}
+}
+
+// Namespaces for the built-in Solana pubsub methods. A namespace ns derives
+// its subscribe/unsubscribe method pair as ns+"Subscribe"/ns+"Unsubscribe",
+// and its notification frames are expected to carry ns+"Notification" (or
+// ns+"Subscription" for JSON-RPC servers using that other convention).
+const (
+	nsProgram   = "program"
+	nsAccount   = "account"
+	nsSignature = "signature"
+	nsSlot      = "slot"
+	nsRoot      = "root"
+	nsVote      = "vote"
+	nsBlock     = "block"
+	nsLogs      = "logs"
+)
+
+func (c *WSClient) ProgramSubscribe(programID string, commitment CommitmentType, opts ...SubscribeOption) (*Subscription[ProgramWSResult], error) {
+	return subscribe[ProgramWSResult](c, []interface{}{programID}, commitment, "jsonParsed", nsProgram, false, opts...)
+}
+
+// AccountSubscribe subscribes to an account to receive notifications
+// any time the lamports or data for that account changes.
+func (c *WSClient) AccountSubscribe(account string, commitment CommitmentType, opts ...SubscribeOption) (*Subscription[AccountWSResult], error) {
+	return c.AccountSubscribeWithOpts(account, commitment, "", opts...)
+}
+
+// AccountSubscribeWithOpts is like AccountSubscribe, but allows specifying
+// the account data encoding (e.g. "base64", "jsonParsed").
+func (c *WSClient) AccountSubscribeWithOpts(account string, commitment CommitmentType, encoding string, opts ...SubscribeOption) (*Subscription[AccountWSResult], error) {
+	return subscribe[AccountWSResult](c, []interface{}{account}, commitment, encoding, nsAccount, false, opts...)
+}
+
+// SignatureSubscribe subscribes to a transaction signature to receive a
+// notification when the transaction is confirmed. This is a one-shot
+// subscription: it automatically closes right after delivering the result.
+func (c *WSClient) SignatureSubscribe(signature string, commitment CommitmentType, opts ...SubscribeOption) (*Subscription[SignatureWSResult], error) {
+	return subscribe[SignatureWSResult](c, []interface{}{signature}, commitment, "", nsSignature, true, opts...)
+}
+
+// SlotSubscribe subscribes to receive notification anytime a slot is processed by the validator.
+func (c *WSClient) SlotSubscribe(opts ...SubscribeOption) (*Subscription[SlotWSResult], error) {
+	return subscribe[SlotWSResult](c, nil, "", "", nsSlot, false, opts...)
+}
+
+// RootSubscribe subscribes to receive notification anytime a new root is set by the validator.
+func (c *WSClient) RootSubscribe(opts ...SubscribeOption) (*Subscription[RootWSResult], error) {
+	return subscribe[RootWSResult](c, nil, "", "", nsRoot, false, opts...)
+}
+
+// VoteSubscribe subscribes to receive notification anytime a new vote is
+// observed in gossip. This subscription is unstable and only available if
+// the validator was started with the --rpc-pubsub-enable-vote-subscription flag.
+func (c *WSClient) VoteSubscribe(opts ...SubscribeOption) (*Subscription[VoteWSResult], error) {
+	return subscribe[VoteWSResult](c, nil, "", "", nsVote, false, opts...)
+}
+
+// BlockSubscribe subscribes to receive notification anytime a new block is confirmed or finalized.
+// This subscription is unstable and only available if the validator was
+// started with the --rpc-pubsub-enable-block-subscription flag.
+func (c *WSClient) BlockSubscribe(filter interface{}, commitment CommitmentType, opts ...SubscribeOption) (*Subscription[BlockWSResult], error) {
+	return subscribe[BlockWSResult](c, []interface{}{filter}, commitment, "", nsBlock, false, opts...)
+}
+
+// LogsSubscribeFilterType selects which transactions logsSubscribe reports
+// on: LogsSubscribeFilterAll or LogsSubscribeFilterAllWithVotes. To scope
+// notifications to a single account, pass the value returned by
+// LogsSubscribeFilterMentions to LogsSubscribe instead.
+type LogsSubscribeFilterType string
+
+const (
+	LogsSubscribeFilterAll          LogsSubscribeFilterType = "all"
+	LogsSubscribeFilterAllWithVotes LogsSubscribeFilterType = "allWithVotes"
+)
+
+// LogsSubscribeFilterMentions restricts logsSubscribe notifications to
+// transactions mentioning the provided account.
+func LogsSubscribeFilterMentions(account string) interface{} {
+	return map[string]interface{}{"mentions": []string{account}}
+}
+
+// LogsSubscribe subscribes to transaction logging. filter is either one of
+// the LogsSubscribeFilter* constants or the result of LogsSubscribeFilterMentions.
+func (c *WSClient) LogsSubscribe(filter interface{}, commitment CommitmentType, opts ...SubscribeOption) (*Subscription[LogsWSResult], error) {
+	return subscribe[LogsWSResult](c, []interface{}{filter}, commitment, "", nsLogs, false, opts...)
+}
+
+// subscribeConfig holds the per-subscription knobs a SubscribeOption can set.
+type subscribeConfig struct {
+	bufferSize   int
+	policy       BackpressurePolicy
+	blockTimeout time.Duration
+	metrics      MetricsRecorder
+}
+
+func defaultSubscribeConfig() *subscribeConfig {
+	return &subscribeConfig{
+		bufferSize:   defaultSubscriptionBufferSize,
+		policy:       BackpressureCloseOnOverflow,
+		blockTimeout: defaultBackpressureTimeout,
+		metrics:      noopMetricsRecorder{},
+	}
+}
+
+// SubscribeOption customizes buffering, backpressure and metrics behavior
+// for a single subscription; pass any number to a *Subscribe method.
+type SubscribeOption func(*subscribeConfig)
+
+// WithBufferSize overrides the subscription's notification channel capacity.
+func WithBufferSize(n int) SubscribeOption {
+	return func(cfg *subscribeConfig) { cfg.bufferSize = n }
+}
+
+// WithBackpressurePolicy overrides how the subscription behaves once its
+// buffer is full. See BackpressurePolicy for the available strategies.
+func WithBackpressurePolicy(policy BackpressurePolicy) SubscribeOption {
+	return func(cfg *subscribeConfig) { cfg.policy = policy }
+}
+
+// WithBlockTimeout sets how long BackpressureBlockWithTimeout waits for
+// buffer space before dropping a message.
+func WithBlockTimeout(d time.Duration) SubscribeOption {
+	return func(cfg *subscribeConfig) { cfg.blockTimeout = d }
+}
+
+// WithMetrics injects a MetricsRecorder to observe this subscription's
+// message counts, decode errors and channel high watermark.
+func WithMetrics(m MetricsRecorder) SubscribeOption {
+	return func(cfg *subscribeConfig) { cfg.metrics = m }
+}
+
+// subscribe is the shared dispatcher behind every typed *Subscribe method:
+// it derives the subscribe/unsubscribe method pair from namespace, builds
+// and sends the JSON-RPC subscribe request, then registers a Subscription[T]
+// that the WSClient's untyped dispatch loop can still drive through the
+// subscription interface.
+func subscribe[T any](c *WSClient, params []interface{}, commitment CommitmentType, encoding, namespace string, oneShot bool, opts ...SubscribeOption) (*Subscription[T], error) {
+	conf := map[string]interface{}{}
+	if encoding != "" {
+		conf["encoding"] = encoding
 	}
 	if commitment != "" {
 		conf["commitment"] = string(commitment)
 	}
 
+	subscriptionMethod := namespace + "Subscribe"
+	unsubscriptionMethod := namespace + "Unsubscribe"
+
 	req := newClientRequest(params, subscriptionMethod, conf)
 	data, err := req.encode()
 	if err != nil {
 		return nil, fmt.Errorf("subscribe: unable to encode subsciption request: %w", err)
 	}
 
-	sub := newSubscription(req, reflect.TypeOf(resultType), func(err error) {
+	cfg := defaultSubscribeConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	sub := newSubscription[T](req, namespace, unsubscriptionMethod, oneShot, cfg, func(err error) {
 		c.closeSubscription(req.ID, err)
 	})
 
@@ -246,7 +836,7 @@ func (c *WSClient) subscribe(params []interface{}, subscriptionMethod, unsubscri
 	zlog.Info("added new subscription to websocket client", zap.Int("count", len(c.subscriptionByRequestID)))
 	c.lock.Unlock()
 
-	err = c.conn.WriteMessage(websocket.TextMessage, data)
+	err = c.getConn().WriteMessage(websocket.TextMessage, data)
 	if err != nil {
 		return nil, fmt.Errorf("unable to write request: %w", err)
 	}
@@ -263,6 +853,63 @@ type ProgramWSResult struct {
 	} `json:"value"`
 }
 
+type AccountWSResult struct {
+	Context struct {
+		Slot uint64
+	} `json:"context"`
+	Value Account `json:"value"`
+}
+
+type SignatureWSResult struct {
+	Context struct {
+		Slot uint64
+	} `json:"context"`
+	Value struct {
+		Err interface{} `json:"err"`
+	} `json:"value"`
+}
+
+type SlotWSResult struct {
+	Parent uint64 `json:"parent"`
+	Root   uint64 `json:"root"`
+	Slot   uint64 `json:"slot"`
+}
+
+// RootWSResult is the newly set root slot number.
+type RootWSResult uint64
+
+type VoteWSResult struct {
+	VotePubkey string   `json:"votePubkey"`
+	Slots      []uint64 `json:"slots"`
+	Hash       string   `json:"hash"`
+	Timestamp  *int64   `json:"timestamp"`
+}
+
+type LogsWSResult struct {
+	Context struct {
+		Slot uint64
+	} `json:"context"`
+	Value struct {
+		Signature string      `json:"signature"`
+		Err       interface{} `json:"err"`
+		Logs      []string    `json:"logs"`
+	} `json:"value"`
+}
+
+// BlockWSResult carries a confirmed or finalized block. Block is left as
+// raw JSON here since the typed block representation lives outside this
+// package's scope.
+type BlockWSResult struct {
+	Context struct {
+		Slot uint64
+	} `json:"context"`
+	Value struct {
+		Slot  uint64          `json:"slot"`
+		Block json.RawMessage `json:"block"`
+		Err   interface{}     `json:"err"`
+	} `json:"value"`
+}
+
 type clientRequest struct {
 	Version string      `json:"jsonrpc"`
 	Method  string      `json:"method"`
@@ -0,0 +1,225 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ws provides a companion server for rpc.WSClient: it speaks the
+// same JSON-RPC subscription wire format, so rpc.WSClient can talk to it in
+// tests without a real validator, enabling deterministic integration tests
+// of decode paths, unsubscribe races and reconnection logic.
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gorilla/websocket"
+)
+
+// clientRequest mirrors rpc's unexported clientRequest wire format so
+// WSServer can parse requests sent by an rpc.WSClient.
+type clientRequest struct {
+	Version string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	ID      uint64          `json:"id"`
+}
+
+// subscribeResponse acknowledges a subscribe/unsubscribe request, mirroring
+// the shape rpc.WSClient expects: {"jsonrpc":"2.0","result":<value>,"id":<reqID>}.
+type subscribeResponse struct {
+	Version string `json:"jsonrpc"`
+	Result  uint64 `json:"result"`
+	ID      uint64 `json:"id"`
+}
+
+// wsClientResponse and wsClientResponseParams mirror rpc's unexported
+// wsClientResponse/wsClientResponseParams shapes, so notifications pushed
+// by WSServer decode cleanly on the rpc.WSClient side.
+type wsClientResponse struct {
+	Version string                  `json:"jsonrpc"`
+	Method  string                  `json:"method"`
+	Params  *wsClientResponseParams `json:"params"`
+}
+
+type wsClientResponseParams struct {
+	Result       interface{} `json:"result"`
+	Subscription uint64      `json:"subscription"`
+}
+
+// HandlerFunc produces notifications for a subscription created with the
+// given raw params. WSServer pushes every value sent on the returned
+// channel to the subscribing client until ctx is canceled (the client
+// disconnected or unsubscribed) or the channel is closed.
+type HandlerFunc func(ctx context.Context, params json.RawMessage) (<-chan interface{}, error)
+
+// WSServer accepts websocket connections speaking the Solana pubsub wire
+// format and dispatches subscribe/unsubscribe requests to registered
+// HandlerFuncs, one per subscribe method (e.g. "programSubscribe").
+type WSServer struct {
+	upgrader websocket.Upgrader
+
+	lock     sync.RWMutex
+	handlers map[string]HandlerFunc
+
+	nextSubID uint64
+}
+
+// NewServer creates an empty WSServer; register handlers with Handle
+// before passing it to http.Serve (it implements http.Handler).
+func NewServer() *WSServer {
+	return &WSServer{
+		handlers: map[string]HandlerFunc{},
+	}
+}
+
+// Handle registers handler for subscribeMethod (e.g. "programSubscribe").
+// Calling Handle again for the same method replaces the previous handler.
+func (s *WSServer) Handle(subscribeMethod string, handler HandlerFunc) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.handlers[subscribeMethod] = handler
+}
+
+func (s *WSServer) handlerFor(method string) (HandlerFunc, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	handler, found := s.handlers[method]
+	return handler, found
+}
+
+func (s *WSServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	go s.serveConn(conn)
+}
+
+func (s *WSServer) serveConn(conn *websocket.Conn) {
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var writeLock sync.Mutex
+	// subID -> cancel for the goroutine pushing that subscription's
+	// notifications; only ever touched from this connection's read loop.
+	cancelBySubID := map[uint64]context.CancelFunc{}
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var req clientRequest
+		if err := json.Unmarshal(message, &req); err != nil {
+			continue
+		}
+
+		if strings.HasSuffix(req.Method, "Unsubscribe") {
+			s.handleUnsubscribe(conn, &writeLock, cancelBySubID, req)
+			continue
+		}
+
+		s.handleSubscribe(ctx, conn, &writeLock, cancelBySubID, req)
+	}
+}
+
+func (s *WSServer) handleSubscribe(ctx context.Context, conn *websocket.Conn, writeLock *sync.Mutex, cancelBySubID map[uint64]context.CancelFunc, req clientRequest) {
+	handler, found := s.handlerFor(req.Method)
+	if !found {
+		return
+	}
+
+	subCtx, cancel := context.WithCancel(ctx)
+	notifications, err := handler(subCtx, req.Params)
+	if err != nil {
+		cancel()
+		return
+	}
+
+	subID := atomic.AddUint64(&s.nextSubID, 1)
+	cancelBySubID[subID] = cancel
+
+	writeLocked(writeLock, func() error {
+		return conn.WriteJSON(subscribeResponse{Version: "2.0", Result: subID, ID: req.ID})
+	})
+
+	notificationMethod := strings.TrimSuffix(req.Method, "Subscribe") + "Notification"
+	go pushNotifications(subCtx, conn, writeLock, notificationMethod, subID, notifications)
+}
+
+func (s *WSServer) handleUnsubscribe(conn *websocket.Conn, writeLock *sync.Mutex, cancelBySubID map[uint64]context.CancelFunc, req clientRequest) {
+	var params []json.RawMessage
+	if err := json.Unmarshal(req.Params, &params); err != nil || len(params) == 0 {
+		return
+	}
+
+	var subID uint64
+	if err := json.Unmarshal(params[0], &subID); err != nil {
+		return
+	}
+
+	if cancel, found := cancelBySubID[subID]; found {
+		cancel()
+		delete(cancelBySubID, subID)
+	}
+
+	writeLocked(writeLock, func() error {
+		return conn.WriteJSON(subscribeResponse{Version: "2.0", Result: 1, ID: req.ID})
+	})
+}
+
+// pushNotifications forwards every value from notifications to conn until
+// subCtx is canceled or the channel closes.
+func pushNotifications(subCtx context.Context, conn *websocket.Conn, writeLock *sync.Mutex, method string, subID uint64, notifications <-chan interface{}) {
+	for {
+		select {
+		case <-subCtx.Done():
+			return
+		case value, ok := <-notifications:
+			if !ok {
+				return
+			}
+			err := writeLocked(writeLock, func() error {
+				return conn.WriteJSON(wsClientResponse{
+					Version: "2.0",
+					Method:  method,
+					Params: &wsClientResponseParams{
+						Result:       value,
+						Subscription: subID,
+					},
+				})
+			})
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+func writeLocked(lock *sync.Mutex, write func() error) error {
+	lock.Lock()
+	defer lock.Unlock()
+	if err := write(); err != nil {
+		return fmt.Errorf("ws server: write: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ws_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dfuse-io/solana-go/rpc"
+	"github.com/dfuse-io/solana-go/rpc/ws"
+	"github.com/stretchr/testify/require"
+)
+
+// slotHandler produces a fresh, independent notification stream (its own
+// slot counter) for every subscribe call, so concurrently active
+// subscriptions never share mutable state.
+func slotHandler(ctx context.Context, params json.RawMessage) (<-chan interface{}, error) {
+	ch := make(chan interface{}, 1)
+	go func() {
+		defer close(ch)
+		var slot uint64
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(10 * time.Millisecond):
+				slot++
+				ch <- map[string]uint64{"slot": slot, "parent": 0, "root": 0}
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// TestWSServerClientRoundTrip drives an rpc.WSClient against a ws.WSServer
+// through a full subscribe/notify/unsubscribe cycle, with no real
+// validator involved. This also guards against the regression where
+// Unsubscribe panicked the receiveMessages goroutine on the unsubscribe ack.
+func TestWSServerClientRoundTrip(t *testing.T) {
+	server := ws.NewServer()
+	server.Handle("slotSubscribe", slotHandler)
+
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(httpServer.URL, "http")
+
+	client, err := rpc.Dial(context.Background(), wsURL)
+	require.NoError(t, err)
+	defer client.Close()
+
+	sub, err := client.SlotSubscribe()
+	require.NoError(t, err)
+
+	result, err := sub.Recv()
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, result.Slot, uint64(1))
+
+	sub.Unsubscribe()
+
+	// A second subscription must still work: the unsubscribe ack for the
+	// first subscription must not have panicked the client's read loop.
+	sub2, err := client.SlotSubscribe()
+	require.NoError(t, err)
+	_, err = sub2.Recv()
+	require.NoError(t, err)
+}
+
+// flakyProxy forwards TCP connections to backendAddr and lets the test sever
+// the single active connection on demand, simulating a network drop that
+// the client must recover from via reconnect.
+type flakyProxy struct {
+	listener net.Listener
+
+	mu      sync.Mutex
+	current net.Conn
+}
+
+func newFlakyProxy(t *testing.T, backendAddr string) *flakyProxy {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	p := &flakyProxy{listener: l}
+	go func() {
+		for {
+			clientConn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			backendConn, err := net.Dial("tcp", backendAddr)
+			if err != nil {
+				clientConn.Close()
+				continue
+			}
+
+			p.mu.Lock()
+			p.current = clientConn
+			p.mu.Unlock()
+
+			go func() {
+				io.Copy(backendConn, clientConn)
+				backendConn.Close()
+				clientConn.Close()
+			}()
+			go func() {
+				io.Copy(clientConn, backendConn)
+				backendConn.Close()
+				clientConn.Close()
+			}()
+		}
+	}()
+	return p
+}
+
+func (p *flakyProxy) sever() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.current != nil {
+		p.current.Close()
+	}
+}
+
+func (p *flakyProxy) addr() string {
+	return p.listener.Addr().String()
+}
+
+func (p *flakyProxy) close() {
+	p.listener.Close()
+}
+
+// TestWSServerReconnectAndResubscribe severs the client's TCP connection
+// mid-stream and verifies the client reconnects through the proxy, redials
+// the still-running WSServer, and keeps receiving notifications on the same
+// Subscription without the caller doing anything.
+func TestWSServerReconnectAndResubscribe(t *testing.T) {
+	server := ws.NewServer()
+	server.Handle("slotSubscribe", slotHandler)
+
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	backendAddr := strings.TrimPrefix(httpServer.URL, "http://")
+	proxy := newFlakyProxy(t, backendAddr)
+	defer proxy.close()
+
+	wsURL := "ws://" + proxy.addr()
+
+	client, err := rpc.Dial(context.Background(), wsURL,
+		rpc.WithReconnectBackoff(10*time.Millisecond, 100*time.Millisecond),
+		rpc.WithMaxReconnectAttempts(20),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	sub, err := client.SlotSubscribe()
+	require.NoError(t, err)
+
+	_, err = sub.Recv()
+	require.NoError(t, err)
+
+	proxy.sever()
+
+	// The client should transparently reconnect and resubscribe; Recv must
+	// keep yielding results on the very same Subscription.
+	deadline := time.After(5 * time.Second)
+	for i := 0; i < 3; i++ {
+		errCh := make(chan error, 1)
+		go func() {
+			_, err := sub.Recv()
+			errCh <- err
+		}()
+		select {
+		case <-deadline:
+			t.Fatal("timed out waiting for notification after reconnect")
+		case err := <-errCh:
+			require.NoError(t, err)
+		}
+	}
+}
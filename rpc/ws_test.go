@@ -0,0 +1,89 @@
+// Copyright 2020 dfuse Platform Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHandleNewSubscriptionMessage_UnknownRequestID guards against a
+// regression where an unsubscribe ack (which carries a fresh request ID
+// that was never stored in subscriptionByRequestID) indexed a map miss and
+// panicked on the nil subscription interface.
+func TestHandleNewSubscriptionMessage_UnknownRequestID(t *testing.T) {
+	c := &WSClient{
+		subscriptionByRequestID: map[uint64]subscription{},
+		subscriptionByWSSubID:   map[uint64]subscription{},
+	}
+
+	assert.NotPanics(t, func() {
+		c.handleNewSubscriptionMessage(12345, 1)
+	})
+	assert.Empty(t, c.subscriptionByWSSubID)
+}
+
+// TestSubscriptionDeliver_DropNewest verifies the BackpressureDropNewest
+// policy discards incoming messages once the buffer is full instead of
+// closing the subscription, and that DroppedCount reflects the discards.
+func TestSubscriptionDeliver_DropNewest(t *testing.T) {
+	req := newClientRequest(nil, "slotSubscribe", map[string]interface{}{})
+	cfg := &subscribeConfig{
+		bufferSize:   1,
+		policy:       BackpressureDropNewest,
+		blockTimeout: time.Second,
+		metrics:      noopMetricsRecorder{},
+	}
+	sub := newSubscription[SlotWSResult](req, nsSlot, "slotUnsubscribe", false, cfg, func(err error) {})
+
+	msg := []byte(`{"jsonrpc":"2.0","params":{"result":{"slot":1,"parent":0,"root":0},"subscription":1}}`)
+	require.NoError(t, sub.dispatch(msg))
+	require.NoError(t, sub.dispatch(msg))
+	require.NoError(t, sub.dispatch(msg))
+
+	assert.EqualValues(t, 2, sub.DroppedCount())
+
+	got, err := sub.Recv()
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, got.Slot)
+}
+
+// TestSubscriptionRecv_ValueBeforeTerminalError guards a one-shot
+// subscription's result against being lost to select's pseudo-random case
+// choice: a value that was enqueued before a terminal error (e.g. the
+// io.EOF closeSubscription sends right after a one-shot's single
+// notification) must always win over that error.
+func TestSubscriptionRecv_ValueBeforeTerminalError(t *testing.T) {
+	req := newClientRequest(nil, "signatureSubscribe", map[string]interface{}{})
+	cfg := defaultSubscribeConfig()
+	sub := newSubscription[SignatureWSResult](req, nsSignature, "signatureUnsubscribe", true, cfg, func(err error) {})
+
+	msg := []byte(`{"jsonrpc":"2.0","params":{"result":{"context":{"slot":1},"value":{"err":null}},"subscription":1}}`)
+	require.NoError(t, sub.dispatch(msg))
+	sub.closeWithErr(io.EOF)
+
+	// Both stream and err are ready at this point; Recv must still surface
+	// the value first, then io.EOF on the following call.
+	got, err := sub.Recv()
+	require.NoError(t, err)
+	require.NotNil(t, got)
+
+	_, err = sub.Recv()
+	assert.Equal(t, io.EOF, err)
+}